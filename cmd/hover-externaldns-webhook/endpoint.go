@@ -0,0 +1,36 @@
+package main
+
+// The types below mirror the minimal subset of the ExternalDNS webhook
+// provider contract (https://kubernetes-sigs.github.io/external-dns/latest/tutorials/webhook-provider/)
+// this server needs; they are not imported from sigs.k8s.io/external-dns to
+// avoid pulling in its full dependency tree for a handful of JSON shapes.
+
+// mediaType is the content type ExternalDNS expects on every response, used
+// during content negotiation on GET /.
+const mediaType = "application/external.dns.webhook+json;version=1"
+
+// Endpoint is a single DNS record as exchanged with ExternalDNS.
+type Endpoint struct {
+	DNSName    string   `json:"dnsName"`
+	Targets    []string `json:"targets"`
+	RecordType string   `json:"recordType"`
+	RecordTTL  int64    `json:"recordTTL,omitempty"`
+}
+
+// Changes is the set of endpoint changes ExternalDNS asks the webhook to
+// apply in a single POST /records call.
+type Changes struct {
+	Create    []*Endpoint `json:"Create"`
+	UpdateOld []*Endpoint `json:"UpdateOld"`
+	UpdateNew []*Endpoint `json:"UpdateNew"`
+	Delete    []*Endpoint `json:"Delete"`
+}
+
+// supportedTypes are the Hover record types this webhook can manage.
+var supportedTypes = map[string]bool{
+	"A":     true,
+	"AAAA":  true,
+	"CNAME": true,
+	"TXT":   true,
+	"MX":    true,
+}
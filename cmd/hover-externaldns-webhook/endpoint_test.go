@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestFQDN(t *testing.T) {
+	tests := []struct {
+		domain, name, want string
+	}{
+		{domain: "example.com", name: "@", want: "example.com."},
+		{domain: "example.com", name: "home", want: "home.example.com."},
+	}
+
+	for _, tt := range tests {
+		if got := fqdn(tt.domain, tt.name); got != tt.want {
+			t.Errorf("fqdn(%q, %q) = %q, want %q", tt.domain, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUnFQDN(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{name: "home.example.com.", want: "home.example.com"},
+		{name: "example.com", want: "example.com"},
+	}
+
+	for _, tt := range tests {
+		if got := unFQDN(tt.name); got != tt.want {
+			t.Errorf("unFQDN(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
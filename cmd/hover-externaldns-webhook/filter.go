@@ -0,0 +1,34 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/FryDay/hoverDDNS/internal/config"
+)
+
+// matchDomain reports whether domain should be exposed through the
+// webhook, given filter. An empty Include matches everything; Exclude
+// always wins over Include.
+func matchDomain(filter config.DomainFilter, domain string) bool {
+	for _, excluded := range filter.Exclude {
+		if matchSuffix(domain, excluded) {
+			return false
+		}
+	}
+
+	if len(filter.Include) == 0 {
+		return true
+	}
+
+	for _, included := range filter.Include {
+		if matchSuffix(domain, included) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchSuffix(domain, pattern string) bool {
+	return domain == pattern || strings.HasSuffix(domain, "."+pattern)
+}
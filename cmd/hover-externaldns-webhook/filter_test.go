@@ -0,0 +1,77 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/FryDay/hoverDDNS/internal/config"
+)
+
+func TestMatchSuffix(t *testing.T) {
+	tests := []struct {
+		domain  string
+		pattern string
+		want    bool
+	}{
+		{domain: "example.com", pattern: "example.com", want: true},
+		{domain: "home.example.com", pattern: "example.com", want: true},
+		{domain: "a.b.example.com", pattern: "example.com", want: true},
+		{domain: "notexample.com", pattern: "example.com", want: false},
+		{domain: "example.com.evil.com", pattern: "example.com", want: false},
+		{domain: "example.org", pattern: "example.com", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := matchSuffix(tt.domain, tt.pattern); got != tt.want {
+			t.Errorf("matchSuffix(%q, %q) = %v, want %v", tt.domain, tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestMatchDomain(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter config.DomainFilter
+		domain string
+		want   bool
+	}{
+		{name: "no filter matches everything", filter: config.DomainFilter{}, domain: "example.com", want: true},
+		{
+			name:   "include matches",
+			filter: config.DomainFilter{Include: []string{"example.com"}},
+			domain: "home.example.com",
+			want:   true,
+		},
+		{
+			name:   "include does not match unrelated domain",
+			filter: config.DomainFilter{Include: []string{"example.com"}},
+			domain: "example.net",
+			want:   false,
+		},
+		{
+			name:   "include does not match non-suffix lookalike",
+			filter: config.DomainFilter{Include: []string{"example.com"}},
+			domain: "notexample.com",
+			want:   false,
+		},
+		{
+			name:   "exclude wins over include",
+			filter: config.DomainFilter{Include: []string{"example.com"}, Exclude: []string{"private.example.com"}},
+			domain: "private.example.com",
+			want:   false,
+		},
+		{
+			name:   "exclude applies with no include list",
+			filter: config.DomainFilter{Exclude: []string{"example.net"}},
+			domain: "example.net",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchDomain(tt.filter, tt.domain); got != tt.want {
+				t.Errorf("matchDomain(%+v, %q) = %v, want %v", tt.filter, tt.domain, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// Command hover-externaldns-webhook serves the ExternalDNS webhook provider
+// HTTP contract backed by a Hover account, so a shared account's zones can
+// be managed from Kubernetes via ExternalDNS.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/FryDay/hoverDDNS/internal/config"
+)
+
+const defaultAddr = "127.0.0.1:8888"
+
+func main() {
+	addr := flag.String("addr", "", "address to listen on (default 127.0.0.1:8888, or the config's webhook_addr)")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	listenAddr := *addr
+	if listenAddr == "" {
+		listenAddr = cfg.WebhookAddr
+	}
+	if listenAddr == "" {
+		listenAddr = defaultAddr
+	}
+
+	provider, err := NewProvider(cfg.Username, cfg.Password, cfg.DomainFilter)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := &server{provider: provider}
+
+	log.Printf("hover-externaldns-webhook: listening on %s", listenAddr)
+	log.Fatal(http.ListenAndServe(listenAddr, s.routes()))
+}
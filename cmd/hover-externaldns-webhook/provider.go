@@ -0,0 +1,193 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/FryDay/hoverDDNS/internal/auth"
+	"github.com/FryDay/hoverDDNS/internal/config"
+	"github.com/FryDay/hoverDDNS/pkg/hover"
+)
+
+// Provider translates between the ExternalDNS webhook contract and the
+// Hover API.
+type Provider struct {
+	client *hover.Client
+	filter config.DomainFilter
+}
+
+// NewProvider returns a Provider authenticated against Hover. For accounts
+// with 2FA enabled, run "hoverddns login" once first so a session is ready
+// in the OS keyring; password may then be left empty.
+func NewProvider(username, password string, filter config.DomainFilter) (*Provider, error) {
+	client, err := auth.Open(username, password)
+	if err != nil {
+		return nil, fmt.Errorf("hover: %w", err)
+	}
+
+	return &Provider{client: client, filter: filter}, nil
+}
+
+// Records returns every supported DNS record across the domains exposed by
+// p.filter, as ExternalDNS Endpoints.
+func (p *Provider) Records() ([]*Endpoint, error) {
+	domains, err := p.client.ListDomains()
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []*Endpoint
+	for _, d := range domains {
+		if !matchDomain(p.filter, d.Name) {
+			continue
+		}
+
+		records, err := p.client.ListRecords(d.Name)
+		if err != nil {
+			return nil, fmt.Errorf("hover: %s: %w", d.Name, err)
+		}
+
+		endpoints = append(endpoints, groupRecords(d.Name, records)...)
+	}
+
+	return endpoints, nil
+}
+
+// ApplyChanges deletes, creates, and recreates Hover records to match
+// changes.
+func (p *Provider) ApplyChanges(changes *Changes) error {
+	domains, err := p.client.ListDomains()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, ep := range changes.Delete {
+		errs = append(errs, p.deleteEndpoint(domains, ep))
+	}
+	for _, ep := range changes.UpdateOld {
+		errs = append(errs, p.deleteEndpoint(domains, ep))
+	}
+	for _, ep := range changes.Create {
+		errs = append(errs, p.createEndpoint(domains, ep))
+	}
+	for _, ep := range changes.UpdateNew {
+		errs = append(errs, p.createEndpoint(domains, ep))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *Provider) resolve(domains []*hover.Domain, ep *Endpoint) (base, name string, err error) {
+	if !supportedTypes[ep.RecordType] {
+		return "", "", fmt.Errorf("hover: unsupported record type %q", ep.RecordType)
+	}
+
+	base, name, err = hover.SplitHost(domains, unFQDN(ep.DNSName))
+	if err != nil {
+		return "", "", err
+	}
+
+	if !matchDomain(p.filter, base) {
+		return "", "", fmt.Errorf("hover: domain %q is not exposed by this webhook", base)
+	}
+
+	return base, name, nil
+}
+
+func (p *Provider) deleteEndpoint(domains []*hover.Domain, ep *Endpoint) error {
+	base, name, err := p.resolve(domains, ep)
+	if err != nil {
+		return err
+	}
+
+	records, err := p.client.ListRecords(base)
+	if err != nil {
+		return err
+	}
+
+	targets := make(map[string]bool, len(ep.Targets))
+	for _, t := range ep.Targets {
+		targets[t] = true
+	}
+
+	var errs []error
+	for _, r := range records {
+		if r.Type != ep.RecordType || r.Name != name || !targets[r.Content] {
+			continue
+		}
+
+		if err := p.client.DeleteRecord(r.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *Provider) createEndpoint(domains []*hover.Domain, ep *Endpoint) error {
+	base, name, err := p.resolve(domains, ep)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, target := range ep.Targets {
+		if _, err := p.client.CreateRecord(base, name, ep.RecordType, target, int(ep.RecordTTL)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// groupRecords converts domain's supported DNS records into Endpoints,
+// combining same name/type records (e.g. round-robin A records) into one
+// Endpoint with multiple Targets.
+func groupRecords(domain string, records []*hover.DNSRecord) []*Endpoint {
+	index := make(map[string]*Endpoint)
+	var order []string
+
+	for _, r := range records {
+		if !supportedTypes[r.Type] {
+			continue
+		}
+
+		key := r.Name + "|" + r.Type
+		ep, ok := index[key]
+		if !ok {
+			ep = &Endpoint{
+				DNSName:    fqdn(domain, r.Name),
+				RecordType: r.Type,
+				RecordTTL:  int64(r.TTL),
+			}
+			index[key] = ep
+			order = append(order, key)
+		}
+
+		ep.Targets = append(ep.Targets, r.Content)
+	}
+
+	endpoints := make([]*Endpoint, 0, len(order))
+	for _, key := range order {
+		endpoints = append(endpoints, index[key])
+	}
+
+	return endpoints
+}
+
+// fqdn joins a Hover record name ("@" for the root) with its domain into a
+// fully-qualified, dot-terminated DNS name.
+func fqdn(domain, name string) string {
+	if name == "@" {
+		return domain + "."
+	}
+
+	return name + "." + domain + "."
+}
+
+// unFQDN strips the trailing dot ExternalDNS puts on DNS names.
+func unFQDN(name string) string {
+	return strings.TrimSuffix(name, ".")
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/FryDay/hoverDDNS/pkg/hover"
+)
+
+func TestGroupRecords(t *testing.T) {
+	records := []*hover.DNSRecord{
+		{Name: "@", Type: "A", Content: "1.1.1.1", TTL: 300},
+		{Name: "@", Type: "A", Content: "2.2.2.2", TTL: 300},
+		{Name: "home", Type: "AAAA", Content: "::1", TTL: 60},
+		{Name: "@", Type: "NS", Content: "ns1.example.com"}, // unsupported, must be dropped
+	}
+
+	got := groupRecords("example.com", records)
+
+	want := []*Endpoint{
+		{DNSName: "example.com.", RecordType: "A", RecordTTL: 300, Targets: []string{"1.1.1.1", "2.2.2.2"}},
+		{DNSName: "home.example.com.", RecordType: "AAAA", RecordTTL: 60, Targets: []string{"::1"}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupRecords() = %+v, want %+v", derefAll(got), derefAll(want))
+	}
+}
+
+func derefAll(eps []*Endpoint) []Endpoint {
+	out := make([]Endpoint, len(eps))
+	for i, ep := range eps {
+		out[i] = *ep
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// server wires Provider up to the ExternalDNS webhook HTTP contract.
+type server struct {
+	provider *Provider
+}
+
+func (s *server) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleNegotiate)
+	mux.HandleFunc("/records", s.handleRecords)
+	mux.HandleFunc("/adjustendpoints", s.handleAdjustEndpoints)
+
+	return mux
+}
+
+// handleNegotiate answers ExternalDNS's initial content negotiation
+// request with the domain filter this webhook exposes.
+func (s *server) handleNegotiate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", mediaType)
+	s.writeJSON(w, s.provider.filter)
+}
+
+func (s *server) handleRecords(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		endpoints, err := s.provider.Records()
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", mediaType)
+		s.writeJSON(w, endpoints)
+
+	case http.MethodPost:
+		var changes Changes
+		if err := json.NewDecoder(r.Body).Decode(&changes); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err := s.provider.ApplyChanges(&changes); err != nil {
+			s.writeError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAdjustEndpoints lets the provider normalize endpoints before
+// ExternalDNS plans changes against them. Hover records need no
+// adjustment, so this is a pass-through.
+func (s *server) handleAdjustEndpoints(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var endpoints []*Endpoint
+	if err := json.NewDecoder(r.Body).Decode(&endpoints); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	s.writeJSON(w, endpoints)
+}
+
+func (s *server) writeJSON(w http.ResponseWriter, v interface{}) {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("hover-externaldns-webhook: write response: %v", err)
+	}
+}
+
+func (s *server) writeError(w http.ResponseWriter, err error) {
+	log.Print(err)
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/subcommands"
+
+	"github.com/FryDay/hoverDDNS/internal/auth"
+	"github.com/FryDay/hoverDDNS/internal/config"
+	"github.com/FryDay/hoverDDNS/pkg/hover"
+)
+
+const (
+	defaultPollInterval = 5 * time.Minute
+	maxBackoff          = 30 * time.Minute
+)
+
+type daemonCmd struct {
+	interval    time.Duration
+	metricsAddr string
+}
+
+func (*daemonCmd) Name() string     { return "daemon" }
+func (*daemonCmd) Synopsis() string { return "run in the foreground, polling for IP changes" }
+func (*daemonCmd) Usage() string {
+	return "daemon [-interval=5m] [-metrics-addr=:9112]:\n" +
+		"  Run in the foreground, polling for IP changes until interrupted.\n"
+}
+
+func (d *daemonCmd) SetFlags(f *flag.FlagSet) {
+	f.DurationVar(&d.interval, "interval", 0, "how often to check for IP changes (default 5m, or the config's poll_interval)")
+	f.StringVar(&d.metricsAddr, "metrics-addr", "", "address to serve Prometheus /metrics on, e.g. \":9112\" (disabled if empty)")
+}
+
+func (d *daemonCmd) Execute(ctx context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	interval := d.interval
+	if interval == 0 {
+		interval = parsePollInterval(cfg.PollInterval)
+	}
+
+	metricsAddr := d.metricsAddr
+	if metricsAddr == "" {
+		metricsAddr = cfg.MetricsAddr
+	}
+	if metricsAddr != "" {
+		go serveMetrics(metricsAddr)
+	}
+
+	client, err := auth.Open(cfg.Username, cfg.Password)
+	if err != nil {
+		loginFailures.Inc()
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	d.run(ctx, client, cfg, interval)
+
+	return subcommands.ExitSuccess
+}
+
+// run polls for IP changes every interval, re-authenticating on an expired
+// session and backing off exponentially on other errors, until ctx is
+// canceled.
+func (d *daemonCmd) run(ctx context.Context, client *hover.Client, cfg *config.Config, interval time.Duration) {
+	backoff := interval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		changed, ipv4, ipv6, err := runUpdate(client, cfg)
+
+		switch {
+		case errors.Is(err, hover.ErrUnauthorized):
+			log.Print("hover: session expired, re-authenticating")
+			if fresh, err := auth.Open(cfg.Username, cfg.Password); err != nil {
+				loginFailures.Inc()
+				log.Print(err)
+			} else {
+				client = fresh
+			}
+
+		case err != nil:
+			// Even a partial failure (e.g. one host out of several)
+			// must count against hoverddns_update_errors_total and
+			// back off, rather than being swallowed as success.
+			updateErrors.Inc()
+			log.Print(err)
+
+			if changed {
+				if serr := config.Save(cfg); serr != nil {
+					log.Print(serr)
+				}
+			}
+
+			backoff = nextBackoff(backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			continue
+
+		default:
+			backoff = interval
+			setCurrentIP("v4", ipv4)
+			setCurrentIP("v6", ipv6)
+			lastUpdateTimestamp.SetToCurrentTime()
+
+			if err := config.Save(cfg); err != nil {
+				log.Print(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func nextBackoff(cur time.Duration) time.Duration {
+	next := cur * 2
+	if next > maxBackoff {
+		return maxBackoff
+	}
+
+	return next
+}
+
+func parsePollInterval(s string) time.Duration {
+	if s == "" {
+		return defaultPollInterval
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		log.Printf("hover: invalid poll_interval %q, using default: %v", s, err)
+		return defaultPollInterval
+	}
+
+	return d
+}
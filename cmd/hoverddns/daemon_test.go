@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextBackoff(t *testing.T) {
+	tests := []struct {
+		cur  time.Duration
+		want time.Duration
+	}{
+		{cur: time.Minute, want: 2 * time.Minute},
+		{cur: 20 * time.Minute, want: 30 * time.Minute},
+		{cur: maxBackoff, want: maxBackoff},
+		{cur: 2 * maxBackoff, want: maxBackoff},
+	}
+
+	for _, tt := range tests {
+		if got := nextBackoff(tt.cur); got != tt.want {
+			t.Errorf("nextBackoff(%v) = %v, want %v", tt.cur, got, tt.want)
+		}
+	}
+}
+
+func TestParsePollInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want time.Duration
+	}{
+		{name: "empty uses default", in: "", want: defaultPollInterval},
+		{name: "valid duration", in: "10m", want: 10 * time.Minute},
+		{name: "invalid duration uses default", in: "not-a-duration", want: defaultPollInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsePollInterval(tt.in); got != tt.want {
+				t.Errorf("parsePollInterval(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/google/subcommands"
+
+	"github.com/FryDay/hoverDDNS/internal/auth"
+	"github.com/FryDay/hoverDDNS/internal/config"
+	"github.com/FryDay/hoverDDNS/pkg/hover"
+)
+
+type listCmd struct{}
+
+func (*listCmd) Name() string { return "list" }
+func (*listCmd) Synopsis() string {
+	return "print the configured domains and their current A/AAAA records"
+}
+func (*listCmd) Usage() string {
+	return "list:\n  Print the configured domains and their current A/AAAA records.\n"
+}
+func (*listCmd) SetFlags(_ *flag.FlagSet) {}
+
+func (*listCmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	client, err := auth.Open(cfg.Username, cfg.Password)
+	if err != nil {
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	domains, err := client.ListDomains()
+	if err != nil {
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	for _, host := range cfg.Domains {
+		base, name, err := hover.SplitHost(domains, host)
+		if err != nil {
+			fmt.Printf("%s: %v\n", host, err)
+			continue
+		}
+
+		records, err := client.ListRecords(base)
+		if err != nil {
+			fmt.Printf("%s: %v\n", host, err)
+			continue
+		}
+
+		fmt.Println(host)
+		for _, r := range records {
+			if r.Name != name || (r.Type != "A" && r.Type != "AAAA") {
+				continue
+			}
+			fmt.Printf("  %s %s\n", r.Type, r.Content)
+		}
+	}
+
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/google/subcommands"
+	"golang.org/x/term"
+
+	"github.com/FryDay/hoverDDNS/internal/config"
+	"github.com/FryDay/hoverDDNS/internal/session"
+	"github.com/FryDay/hoverDDNS/pkg/hover"
+)
+
+type loginCmd struct{}
+
+func (*loginCmd) Name() string     { return "login" }
+func (*loginCmd) Synopsis() string { return "authenticate against Hover and save the session" }
+func (*loginCmd) Usage() string {
+	return "login:\n" +
+		"  Authenticate against Hover, prompting for a 2FA code if the account\n" +
+		"  requires one, and save the resulting session to the OS keyring. The\n" +
+		"  config's password is cleared once this has run, since every other\n" +
+		"  subcommand restores the session from the keyring instead.\n"
+}
+func (*loginCmd) SetFlags(_ *flag.FlagSet) {}
+
+func (*loginCmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+
+	username := cfg.Username
+	if username == "" {
+		username, err = prompt(stdin, "Hover username: ")
+		if err != nil {
+			log.Print(err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	password := cfg.Password
+	if password == "" {
+		password, err = promptPassword("Hover password: ")
+		if err != nil {
+			log.Print(err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	client := hover.NewClient(username, password)
+
+	requires2FA, err := client.Login()
+	if err != nil {
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	if requires2FA {
+		code, err := prompt(stdin, "Two-factor code: ")
+		if err != nil {
+			log.Print(err)
+			return subcommands.ExitFailure
+		}
+
+		if err := client.SubmitTwoFactorCode(code); err != nil {
+			log.Print(err)
+			return subcommands.ExitFailure
+		}
+	}
+
+	token, ok := client.SessionToken()
+	if !ok {
+		log.Print("hover: login did not produce a session")
+		return subcommands.ExitFailure
+	}
+
+	if err := session.Save(username, token); err != nil {
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	cfg.Username = username
+	cfg.Password = ""
+	if err := config.Save(cfg); err != nil {
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	fmt.Println("hover: login successful, session saved to the OS keyring")
+
+	return subcommands.ExitSuccess
+}
+
+func prompt(r *bufio.Reader, label string) (string, error) {
+	fmt.Print(label)
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+func promptPassword(label string) (string, error) {
+	fmt.Print(label)
+
+	b, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,24 @@
+// Command hoverddns keeps Hover-hosted DNS records pointed at the host's
+// current public IP address.
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+
+	"github.com/google/subcommands"
+)
+
+func main() {
+	subcommands.Register(subcommands.HelpCommand(), "")
+	subcommands.Register(subcommands.FlagsCommand(), "")
+	subcommands.Register(subcommands.CommandsCommand(), "")
+	subcommands.Register(&updateCmd{}, "")
+	subcommands.Register(&daemonCmd{}, "")
+	subcommands.Register(&listCmd{}, "")
+	subcommands.Register(&loginCmd{}, "")
+
+	flag.Parse()
+	os.Exit(int(subcommands.Execute(context.Background())))
+}
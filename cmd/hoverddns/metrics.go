@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	lastUpdateTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "hoverddns_last_update_timestamp",
+		Help: "Unix timestamp of the last successful DNS update.",
+	})
+	currentIP = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hoverddns_current_ip",
+		Help: "The most recently detected public IP address, by family.",
+	}, []string{"family", "ip"})
+	updateErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "hoverddns_update_errors_total",
+		Help: "Number of poll cycles that failed to update every domain.",
+	})
+	loginFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "hoverddns_login_failures_total",
+		Help: "Number of failed Hover login attempts.",
+	})
+)
+
+// setCurrentIP records ip as the most recently detected address for family,
+// replacing whatever was previously recorded for it.
+func setCurrentIP(family, ip string) {
+	if ip == "" {
+		return
+	}
+
+	currentIP.DeletePartialMatch(prometheus.Labels{"family": family})
+	currentIP.WithLabelValues(family, ip).Set(1)
+}
+
+// serveMetrics serves the Prometheus /metrics endpoint on addr until the
+// process exits. Errors are logged, not fatal, since the metrics server is
+// a convenience, not core to the daemon's job.
+func serveMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("hover: metrics server: %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/subcommands"
+
+	"github.com/FryDay/hoverDDNS/internal/auth"
+	"github.com/FryDay/hoverDDNS/internal/config"
+	"github.com/FryDay/hoverDDNS/pkg/hover"
+	"github.com/FryDay/hoverDDNS/pkg/ipdetect"
+)
+
+type updateCmd struct{}
+
+func (*updateCmd) Name() string { return "update" }
+func (*updateCmd) Synopsis() string {
+	return "push the current public IP to the configured Hover domains"
+}
+func (*updateCmd) Usage() string {
+	return "update:\n  Detect the current public IP and push it to every domain in the config.\n"
+}
+func (*updateCmd) SetFlags(_ *flag.FlagSet) {}
+
+func (*updateCmd) Execute(_ context.Context, _ *flag.FlagSet, _ ...interface{}) subcommands.ExitStatus {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	client, err := auth.Open(cfg.Username, cfg.Password)
+	if err != nil {
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	changed, _, _, err := runUpdate(client, cfg)
+	if changed {
+		if serr := config.Save(cfg); serr != nil {
+			log.Print(serr)
+			return subcommands.ExitFailure
+		}
+	}
+	if err != nil {
+		log.Print(err)
+		return subcommands.ExitFailure
+	}
+
+	return subcommands.ExitSuccess
+}
+
+// runUpdate detects the host's public IPv4/IPv6 addresses and pushes any
+// changes to every domain in cfg.Domains. It returns whether cfg was
+// modified, the detected addresses (empty if that family is unavailable),
+// and a joined error if neither family could be detected, the initial
+// ListDomains call failed outright (e.g. an expired session), or any host
+// failed to update — one host failing does not stop the rest from being
+// tried.
+func runUpdate(client *hover.Client, cfg *config.Config) (changed bool, ipv4, ipv6 string, err error) {
+	detector := &ipdetect.Detector{
+		Providers: cfg.IPProviders,
+		Mode:      cfg.IPMode,
+		Timeout:   5 * time.Second,
+	}
+
+	return doUpdate(client, cfg, detector.Detect)
+}
+
+// detectFunc resolves the host's current public address for family. It is
+// a parameter of doUpdate, rather than doUpdate calling ipdetect directly,
+// so tests can substitute a fake detector instead of reaching the network.
+type detectFunc func(family ipdetect.Family) (string, error)
+
+func doUpdate(client *hover.Client, cfg *config.Config, detect detectFunc) (changed bool, ipv4, ipv6 string, err error) {
+	var errv4, errv6 error
+	ipv4, errv4 = detect(ipdetect.IPv4)
+	ipv6, errv6 = detect(ipdetect.IPv6)
+	if errv4 != nil && errv6 != nil {
+		return false, "", "", fmt.Errorf("hover: could not detect a public IPv4 or IPv6 address")
+	}
+	if errv4 != nil {
+		ipv4 = ""
+	}
+	if errv6 != nil {
+		ipv6 = ""
+	}
+
+	domains, err := client.ListDomains()
+	if err != nil {
+		return false, ipv4, ipv6, err
+	}
+
+	var errs []error
+	for _, host := range cfg.Domains {
+		state := cfg.IPs[host]
+		if state == nil {
+			state = &config.HostIPs{}
+		}
+
+		if ipv4 != "" && ipv4 != state.IPv4 {
+			if err := updateDNS(client, domains, host, "A", ipv4); err != nil {
+				errs = append(errs, fmt.Errorf("hover: %s: %w", host, err))
+			} else {
+				state.IPv4 = ipv4
+				changed = true
+			}
+		}
+
+		if ipv6 != "" && ipv6 != state.IPv6 {
+			if err := updateDNS(client, domains, host, "AAAA", ipv6); err != nil {
+				errs = append(errs, fmt.Errorf("hover: %s: %w", host, err))
+			} else {
+				state.IPv6 = ipv6
+				changed = true
+			}
+		}
+
+		cfg.IPs[host] = state
+	}
+
+	return changed, ipv4, ipv6, errors.Join(errs...)
+}
+
+// updateDNS pushes ip to the rtype record matching host (e.g. "example.com"
+// or "home.example.com") in domains.
+func updateDNS(client *hover.Client, domains []*hover.Domain, host, rtype, ip string) error {
+	base, name, err := hover.SplitHost(domains, host)
+	if err != nil {
+		return err
+	}
+
+	records, err := client.ListRecords(base)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		if r.Type != rtype || r.Name != name {
+			continue
+		}
+
+		if err := client.UpdateRecord(r.ID, ip); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
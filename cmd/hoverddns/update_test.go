@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FryDay/hoverDDNS/internal/config"
+	"github.com/FryDay/hoverDDNS/pkg/hover"
+	"github.com/FryDay/hoverDDNS/pkg/ipdetect"
+)
+
+// withHoverServer points hover.BaseURL at a test server driven by handler
+// and returns an already-authenticated Client.
+func withHoverServer(t *testing.T, handler http.HandlerFunc) *hover.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	prevBaseURL := hover.BaseURL
+	hover.BaseURL = srv.URL
+	t.Cleanup(func() { hover.BaseURL = prevBaseURL })
+
+	client := hover.NewClient("user", "pass")
+	client.SetSessionToken("test-session")
+
+	return client
+}
+
+func detectFixed(ipv4, ipv6 string) detectFunc {
+	return func(family ipdetect.Family) (string, error) {
+		if family == ipdetect.IPv4 {
+			return ipv4, nil
+		}
+		return ipv6, nil
+	}
+}
+
+func newTestConfig(hosts ...string) *config.Config {
+	return &config.Config{
+		Domains: hosts,
+		IPs:     make(map[string]*config.HostIPs),
+	}
+}
+
+func TestDoUpdatePushesChangedAddresses(t *testing.T) {
+	var updated []string
+
+	client := withHoverServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/dns":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"succeeded": true,
+				"domains": []map[string]interface{}{
+					{
+						"id": "dom1", "domain_name": "example.com", "active": true,
+						"entries": []map[string]interface{}{
+							{"id": "rec-a", "name": "@", "type": "A", "content": "1.1.1.1"},
+						},
+					},
+				},
+			})
+
+		case r.Method == http.MethodPut && r.URL.Path == "/dns/rec-a":
+			updated = append(updated, r.URL.Query().Get("content"))
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true})
+
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	cfg := newTestConfig("example.com")
+
+	changed, ipv4, ipv6, err := doUpdate(client, cfg, detectFixed("2.2.2.2", ""))
+	if err != nil {
+		t.Fatalf("doUpdate: %v", err)
+	}
+	if !changed {
+		t.Error("doUpdate did not report a change for a new address")
+	}
+	if ipv4 != "2.2.2.2" || ipv6 != "" {
+		t.Errorf("doUpdate addresses = %q, %q, want %q, %q", ipv4, ipv6, "2.2.2.2", "")
+	}
+	if len(updated) != 1 || updated[0] != "2.2.2.2" {
+		t.Errorf("UpdateRecord calls = %v, want one call with 2.2.2.2", updated)
+	}
+	if cfg.IPs["example.com"].IPv4 != "2.2.2.2" {
+		t.Errorf("cfg.IPs[example.com].IPv4 = %q, want %q", cfg.IPs["example.com"].IPv4, "2.2.2.2")
+	}
+}
+
+func TestDoUpdateSkipsUnchangedAddress(t *testing.T) {
+	var updateCalls int
+
+	client := withHoverServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dns" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"succeeded": true,
+				"domains": []map[string]interface{}{
+					{"id": "dom1", "domain_name": "example.com", "active": true},
+				},
+			})
+			return
+		}
+
+		updateCalls++
+	})
+
+	cfg := newTestConfig("example.com")
+	cfg.IPs["example.com"] = &config.HostIPs{IPv4: "1.1.1.1"}
+
+	changed, _, _, err := doUpdate(client, cfg, detectFixed("1.1.1.1", ""))
+	if err != nil {
+		t.Fatalf("doUpdate: %v", err)
+	}
+	if changed {
+		t.Error("doUpdate reported a change when the detected address already matched")
+	}
+	if updateCalls != 0 {
+		t.Errorf("doUpdate made %d update calls for an unchanged address, want 0", updateCalls)
+	}
+}
+
+func TestDoUpdateSurfacesErrorWhenEveryHostFails(t *testing.T) {
+	client := withHoverServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dns":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"succeeded": true,
+				"domains": []map[string]interface{}{
+					{
+						"id": "dom1", "domain_name": "example.com", "active": true,
+						"entries": []map[string]interface{}{
+							{"id": "rec-a", "name": "@", "type": "A", "content": "1.1.1.1"},
+						},
+					},
+					{
+						"id": "dom2", "domain_name": "example.net", "active": true,
+						"entries": []map[string]interface{}{
+							{"id": "rec-b", "name": "@", "type": "A", "content": "1.1.1.1"},
+						},
+					},
+				},
+			})
+
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": false, "error": "rate limited"})
+		}
+	})
+
+	cfg := newTestConfig("example.com", "example.net")
+
+	changed, _, _, err := doUpdate(client, cfg, detectFixed("2.2.2.2", ""))
+	if err == nil {
+		t.Fatal("doUpdate succeeded even though every host's update failed, want error")
+	}
+	if changed {
+		t.Error("doUpdate reported a change even though every host's update failed")
+	}
+}
+
+func TestDoUpdateContinuesPastOneHostFailure(t *testing.T) {
+	client := withHoverServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/dns":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"succeeded": true,
+				"domains": []map[string]interface{}{
+					{
+						"id": "dom1", "domain_name": "example.com", "active": true,
+						"entries": []map[string]interface{}{
+							{"id": "rec-ok", "name": "@", "type": "A", "content": "1.1.1.1"},
+						},
+					},
+					{
+						"id": "dom2", "domain_name": "example.net", "active": true,
+						"entries": []map[string]interface{}{
+							{"id": "rec-fail", "name": "@", "type": "A", "content": "1.1.1.1"},
+						},
+					},
+				},
+			})
+
+		case r.URL.Path == "/dns/rec-ok":
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true})
+
+		case r.URL.Path == "/dns/rec-fail":
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": false, "error": "quota exceeded"})
+
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+	})
+
+	cfg := newTestConfig("example.com", "example.net")
+
+	changed, _, _, err := doUpdate(client, cfg, detectFixed("2.2.2.2", ""))
+	if err == nil {
+		t.Fatal("doUpdate did not surface the failing host's error")
+	}
+	if !changed {
+		t.Error("doUpdate did not report the successful host's change")
+	}
+	if cfg.IPs["example.com"].IPv4 != "2.2.2.2" {
+		t.Errorf("successful host's state = %q, want %q", cfg.IPs["example.com"].IPv4, "2.2.2.2")
+	}
+	if cfg.IPs["example.net"] != nil && cfg.IPs["example.net"].IPv4 == "2.2.2.2" {
+		t.Error("failing host's state was updated despite the update failing")
+	}
+}
+
+func TestDoUpdateNoAddressDetected(t *testing.T) {
+	client := withHoverServer(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s", r.URL.Path)
+	})
+
+	cfg := newTestConfig("example.com")
+
+	_, _, _, err := doUpdate(client, cfg, func(ipdetect.Family) (string, error) {
+		return "", fmt.Errorf("ipdetect: no provider returned an address")
+	})
+	if err == nil {
+		t.Fatal("doUpdate succeeded with no detected address, want error")
+	}
+}
@@ -0,0 +1,55 @@
+// Package auth resolves a ready-to-use hover.Client for username, the way
+// every hoverDDNS binary should: restore a session previously persisted by
+// "hoverddns login" from the OS keyring, falling back to a plain
+// username/password login only for accounts that don't have 2FA enabled.
+// Accounts with 2FA must run "hoverddns login" once interactively, since
+// there is no way to prompt for a code from a daemon or webhook server.
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/FryDay/hoverDDNS/internal/session"
+	"github.com/FryDay/hoverDDNS/pkg/hover"
+)
+
+// ErrNoSession is returned by Open when username has no valid session and
+// no password was supplied. An account that requires a 2FA code gets a
+// separate error.
+var ErrNoSession = errors.New(`hover: no valid session; run "hoverddns login" first`)
+
+// Open returns an authenticated hover.Client for username. It first tries
+// the session persisted in the OS keyring by a prior "hoverddns login",
+// and only attempts a fresh username/password login if that is missing,
+// expired, or password is all that's available.
+func Open(username, password string) (*hover.Client, error) {
+	client := hover.NewClient(username, password)
+
+	if token, err := session.Load(username); err == nil && token != "" {
+		client.SetSessionToken(token)
+		if _, err := client.ListDomains(); err == nil {
+			return client, nil
+		}
+	}
+
+	if password == "" {
+		return nil, ErrNoSession
+	}
+
+	requires2FA, err := client.Login()
+	if err != nil {
+		return nil, err
+	}
+	if requires2FA {
+		return nil, fmt.Errorf(`hover: account requires a two-factor code; run "hoverddns login"`)
+	}
+
+	if token, ok := client.SessionToken(); ok {
+		if err := session.Save(username, token); err != nil {
+			return client, fmt.Errorf("hover: authenticated, but could not persist session: %w", err)
+		}
+	}
+
+	return client, nil
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/FryDay/hoverDDNS/internal/session"
+	"github.com/FryDay/hoverDDNS/pkg/hover"
+)
+
+// withServer points hover.BaseURL at a test server driven by validSession
+// (which decides whether a hoverauth cookie on /dns is still good) and
+// requires2FA (whether a fresh /login needs a 2FA code).
+func withServer(t *testing.T, validSession func(string) bool, requires2FA bool) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dns":
+			cookie, err := r.Cookie("hoverauth")
+			if err != nil || !validSession(cookie.Value) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true, "domains": []interface{}{}})
+
+		case "/login":
+			if requires2FA {
+				http.SetCookie(w, &http.Cookie{Name: "hover_session", Value: "pending-challenge"})
+			} else {
+				http.SetCookie(w, &http.Cookie{Name: "hoverauth", Value: "fresh-session"})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true})
+
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	prevBaseURL := hover.BaseURL
+	hover.BaseURL = srv.URL
+	t.Cleanup(func() { hover.BaseURL = prevBaseURL })
+}
+
+func TestOpenRestoresValidSession(t *testing.T) {
+	keyring.MockInit()
+	withServer(t, func(token string) bool { return token == "keyring-session" }, false)
+
+	if err := session.Save("user", "keyring-session"); err != nil {
+		t.Fatalf("session.Save: %v", err)
+	}
+
+	client, err := Open("user", "")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	token, ok := client.SessionToken()
+	if !ok || token != "keyring-session" {
+		t.Errorf("Open restored session %q, want %q", token, "keyring-session")
+	}
+}
+
+func TestOpenFallsBackToPasswordOnExpiredSession(t *testing.T) {
+	keyring.MockInit()
+	withServer(t, func(token string) bool { return token == "fresh-session" }, false)
+
+	if err := session.Save("user", "stale-session"); err != nil {
+		t.Fatalf("session.Save: %v", err)
+	}
+
+	client, err := Open("user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	token, ok := client.SessionToken()
+	if !ok || token != "fresh-session" {
+		t.Errorf("Open() session = %q, want %q", token, "fresh-session")
+	}
+
+	saved, err := session.Load("user")
+	if err != nil || saved != "fresh-session" {
+		t.Errorf("Open did not persist the refreshed session to the keyring: %q, %v", saved, err)
+	}
+}
+
+func TestOpenLogsInWithoutAnyPriorSession(t *testing.T) {
+	keyring.MockInit()
+	withServer(t, func(string) bool { return false }, false)
+
+	client, err := Open("user", "pass")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	if _, ok := client.SessionToken(); !ok {
+		t.Error("Open returned a client with no session")
+	}
+}
+
+func TestOpenNoSessionNoPassword(t *testing.T) {
+	keyring.MockInit()
+	withServer(t, func(string) bool { return false }, false)
+
+	if _, err := Open("user", ""); err != ErrNoSession {
+		t.Fatalf("Open error = %v, want ErrNoSession", err)
+	}
+}
+
+func TestOpenRequiresTwoFactor(t *testing.T) {
+	keyring.MockInit()
+	withServer(t, func(string) bool { return false }, true)
+
+	if _, err := Open("user", "pass"); err == nil {
+		t.Fatal("Open succeeded for an account requiring 2FA, want error")
+	}
+}
@@ -0,0 +1,136 @@
+// Package config loads and saves the shared ~/.config/hover.json config
+// file used by every hoverDDNS binary.
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path"
+
+	"github.com/spf13/viper"
+)
+
+var defaultConfig = `{
+	"username": "",
+	"password": "",
+	"domains": []
+}`
+
+// HostIPs is the last address successfully pushed to a host, per family.
+type HostIPs struct {
+	IPv4 string `json:"ipv4,omitempty"`
+	IPv6 string `json:"ipv6,omitempty"`
+}
+
+// DomainFilter restricts which zones a shared Hover account exposes to a
+// consumer such as the ExternalDNS webhook.
+type DomainFilter struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Config ...
+type Config struct {
+	Username string `json:"username"`
+	// Password is used for a fresh username/password login whenever no
+	// valid session is in the OS keyring. "hoverddns login" exchanges it
+	// for a session saved to the keyring and clears this field. Accounts
+	// with 2FA enabled must run that subcommand interactively rather than
+	// relying on Password alone.
+	Password string `json:"password"`
+	// Domains are the hosts hoverddns keeps in sync, e.g. "example.com"
+	// for the root domain or "home.example.com" for a subdomain.
+	Domains []string `json:"domains"`
+	// IPs is the last IP successfully pushed for each entry in Domains,
+	// keyed by host.
+	IPs map[string]*HostIPs `json:"ips"`
+	// IPProviders restricts which ipdetect providers are queried. Empty
+	// uses every built-in provider.
+	IPProviders []string `json:"ip_providers,omitempty" mapstructure:"ip_providers"`
+	// IPMode is the ipdetect aggregation mode ("first" or "majority").
+	// Empty defaults to "first".
+	IPMode string `json:"ip_mode,omitempty" mapstructure:"ip_mode"`
+	// PollInterval is how often the daemon subcommand checks for IP
+	// changes, e.g. "5m". The daemon's -interval flag overrides it.
+	PollInterval string `json:"poll_interval,omitempty" mapstructure:"poll_interval"`
+	// MetricsAddr is the address the daemon subcommand serves /metrics
+	// on, e.g. ":9112". Empty disables the metrics server. The daemon's
+	// -metrics-addr flag overrides it.
+	MetricsAddr string `json:"metrics_addr,omitempty" mapstructure:"metrics_addr"`
+	// DomainFilter restricts which zones the ExternalDNS webhook exposes.
+	// Empty includes every domain on the account.
+	DomainFilter DomainFilter `json:"domain_filter,omitempty" mapstructure:"domain_filter"`
+	// WebhookAddr is the address the ExternalDNS webhook binary listens
+	// on. Its -addr flag overrides it.
+	WebhookAddr string `json:"webhook_addr,omitempty" mapstructure:"webhook_addr"`
+}
+
+func dir() (string, error) {
+	usr, err := user.Current()
+	if err != nil {
+		return "", err
+	}
+
+	confpath := path.Join(usr.HomeDir, ".config")
+	if _, err := os.Stat(confpath); os.IsNotExist(err) {
+		if err := os.Mkdir(confpath, 0700); err != nil {
+			return "", err
+		}
+	}
+
+	return confpath, nil
+}
+
+// Load reads ~/.config/hover.json, creating it with defaults if it does
+// not exist.
+func Load() (*Config, error) {
+	confpath, err := dir()
+	if err != nil {
+		return nil, err
+	}
+
+	// The IPs map is keyed by host ("example.com"), so viper's default "."
+	// key delimiter would split those keys into nested maps.
+	v := viper.NewWithOptions(viper.KeyDelimiter("::"))
+	v.SetConfigName("hover")
+	v.SetConfigType("json")
+	v.AddConfigPath(confpath)
+	if _, ok := v.ReadInConfig().(viper.ConfigFileNotFoundError); ok {
+		if err := ioutil.WriteFile(path.Join(confpath, "hover.json"), []byte(defaultConfig), 0600); err != nil {
+			return nil, err
+		}
+	}
+
+	config := &Config{}
+	if err := v.Unmarshal(config); err != nil {
+		return nil, err
+	}
+	if len(config.Domains) == 0 {
+		// Back-compat with the old single "domain" string config.
+		if d := v.GetString("domain"); d != "" {
+			config.Domains = []string{d}
+		}
+	}
+	if config.IPs == nil {
+		config.IPs = make(map[string]*HostIPs)
+	}
+
+	return config, nil
+}
+
+// Save writes config back to ~/.config/hover.json.
+func Save(config *Config) error {
+	confpath, err := dir()
+	if err != nil {
+		return err
+	}
+
+	confBytes, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path.Join(confpath, "hover.json"), confBytes, 0600)
+}
@@ -0,0 +1,27 @@
+// Package session persists Hover session tokens to the OS-appropriate
+// secret store (libsecret on Linux, Keychain on macOS, Credential Manager
+// on Windows) via go-keyring, so a plaintext password need not be kept
+// around once a hoverddns login has succeeded.
+package session
+
+import "github.com/zalando/go-keyring"
+
+// service is the keyring service name every hoverDDNS binary stores its
+// entries under, keyed by Hover username.
+const service = "hoverddns"
+
+// Save persists token as username's session in the OS keyring.
+func Save(username, token string) error {
+	return keyring.Set(service, username, token)
+}
+
+// Load returns username's previously persisted session token. It returns
+// keyring.ErrNotFound (wrapped) if none has been saved.
+func Load(username string) (string, error) {
+	return keyring.Get(service, username)
+}
+
+// Delete removes username's persisted session token, if any.
+func Delete(username string) error {
+	return keyring.Delete(service, username)
+}
@@ -0,0 +1,34 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestSaveLoadDelete(t *testing.T) {
+	keyring.MockInit()
+
+	if _, err := Load("user@example.com"); err == nil {
+		t.Fatal("Load succeeded before any session was saved, want error")
+	}
+
+	if err := Save("user@example.com", "session-token"); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	token, err := Load("user@example.com")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if token != "session-token" {
+		t.Errorf("Load() = %q, want %q", token, "session-token")
+	}
+
+	if err := Delete("user@example.com"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := Load("user@example.com"); err == nil {
+		t.Fatal("Load succeeded after Delete, want error")
+	}
+}
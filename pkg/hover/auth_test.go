@@ -0,0 +1,170 @@
+package hover
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// withAuthServer points BaseURL at a test server running handler for the
+// duration of the test and returns a fresh, unauthenticated Client.
+func withAuthServer(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	prevBaseURL := BaseURL
+	BaseURL = srv.URL
+	t.Cleanup(func() { BaseURL = prevBaseURL })
+
+	return NewClient("user", "pass")
+}
+
+func TestLoginWithoutTwoFactor(t *testing.T) {
+	client := withAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/login" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: "full-session"})
+		json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true})
+	})
+
+	requires2FA, err := client.Login()
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if requires2FA {
+		t.Fatal("Login reported requires2FA for an account without 2FA")
+	}
+
+	token, ok := client.SessionToken()
+	if !ok || token != "full-session" {
+		t.Errorf("SessionToken() = %q, %v, want %q, true", token, ok, "full-session")
+	}
+}
+
+func TestLoginWithTwoFactor(t *testing.T) {
+	client := withAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: challengeCookieName, Value: "pending-challenge"})
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true, "status": "2fa_required"})
+
+		case "/2fa":
+			cookie, err := r.Cookie(challengeCookieName)
+			if err != nil || cookie.Value != "pending-challenge" {
+				json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": false, "error": "missing challenge"})
+				return
+			}
+
+			if err := r.ParseForm(); err != nil || r.FormValue("code") != "123456" {
+				json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": false, "error": "invalid code"})
+				return
+			}
+
+			http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: "full-session"})
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true})
+
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+
+	requires2FA, err := client.Login()
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if !requires2FA {
+		t.Fatal("Login did not report requires2FA for an account with 2FA enabled")
+	}
+	if _, ok := client.SessionToken(); ok {
+		t.Fatal("SessionToken() ok before the 2FA challenge was answered")
+	}
+
+	if err := client.SubmitTwoFactorCode("123456"); err != nil {
+		t.Fatalf("SubmitTwoFactorCode: %v", err)
+	}
+
+	token, ok := client.SessionToken()
+	if !ok || token != "full-session" {
+		t.Errorf("SessionToken() = %q, %v, want %q, true", token, ok, "full-session")
+	}
+	if client.challenge != nil {
+		t.Error("challenge cookie was not cleared after a successful SubmitTwoFactorCode")
+	}
+}
+
+func TestLoginWithTwoFactorWrongCode(t *testing.T) {
+	client := withAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: challengeCookieName, Value: "pending-challenge"})
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true})
+
+		case "/2fa":
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": false, "error": "invalid code"})
+
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	})
+
+	if _, err := client.Login(); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	if err := client.SubmitTwoFactorCode("000000"); err == nil {
+		t.Fatal("SubmitTwoFactorCode succeeded with a server-rejected code, want error")
+	}
+	if _, ok := client.SessionToken(); ok {
+		t.Error("SessionToken() ok after a rejected 2FA code")
+	}
+}
+
+func TestSubmitTwoFactorCodeWithoutChallenge(t *testing.T) {
+	client := NewClient("user", "pass")
+
+	if err := client.SubmitTwoFactorCode("123456"); err != ErrNoChallenge {
+		t.Fatalf("SubmitTwoFactorCode error = %v, want ErrNoChallenge", err)
+	}
+}
+
+func TestLoginFailure(t *testing.T) {
+	client := withAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": false, "error": "invalid credentials"})
+	})
+
+	if _, err := client.Login(); err == nil {
+		t.Fatal("Login succeeded with a server-rejected password, want error")
+	}
+}
+
+// TestLoginFailureClearsStaleSession pins that a rejected Login does not
+// report success just because the Client already held a cookie from an
+// earlier SetSessionToken or Login call.
+func TestLoginFailureClearsStaleSession(t *testing.T) {
+	client := withAuthServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": false, "error": "invalid credentials"})
+	})
+	client.SetSessionToken("stale-session")
+
+	if _, err := client.Login(); err == nil {
+		t.Fatal("Login succeeded with a server-rejected password while a stale session cookie was set, want error")
+	}
+	if _, ok := client.SessionToken(); ok {
+		t.Error("SessionToken() ok after a failed Login, want the stale session cleared")
+	}
+}
+
+func TestSetSessionToken(t *testing.T) {
+	client := NewClient("user", "pass")
+	client.SetSessionToken("restored-session")
+
+	token, ok := client.SessionToken()
+	if !ok || token != "restored-session" {
+		t.Errorf("SessionToken() = %q, %v, want %q, true", token, ok, "restored-session")
+	}
+}
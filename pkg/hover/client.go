@@ -0,0 +1,417 @@
+// Package hover implements a small client for the (unofficial) Hover DNS API,
+// the same endpoints the Hover web UI itself talks to.
+package hover
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// BaseURL is the Hover API endpoint used by all requests. It is a var
+// rather than a const so tests can point it at a local test server.
+var BaseURL = "https://www.hover.com/api"
+
+// ErrUnauthorized is returned when Hover answers a request with 401
+// Unauthorized, e.g. because the session cookie has expired. Callers should
+// Login again and retry.
+var ErrUnauthorized = errors.New("hover: unauthorized")
+
+// Domain is a domain owned by the Hover account.
+type Domain struct {
+	ID     string `json:"id"`
+	Name   string `json:"domain_name"`
+	Active bool   `json:"active"`
+}
+
+// DNSRecord is a single DNS entry on a Domain.
+type DNSRecord struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Content   string `json:"content"`
+	TTL       int    `json:"ttl,omitempty"`
+	IsDefault bool   `json:"is_default"`
+	CanRevert bool   `json:"can_revert"`
+}
+
+// SplitHost resolves host (e.g. "example.com" or "home.example.com") to the
+// domain it belongs to and its record name within that domain ("@" for the
+// root, or a subdomain label).
+func SplitHost(domains []*Domain, host string) (base, name string, err error) {
+	for _, d := range domains {
+		if host == d.Name {
+			return d.Name, "@", nil
+		}
+		if strings.HasSuffix(host, "."+d.Name) {
+			return d.Name, strings.TrimSuffix(host, "."+d.Name), nil
+		}
+	}
+
+	return "", "", fmt.Errorf("hover: domain not owned: %s", host)
+}
+
+// sessionCookieName is the cookie Hover sets once a login has fully
+// succeeded (i.e. password, and 2FA code if the account has it enabled).
+const sessionCookieName = "hoverauth"
+
+// challengeCookieName is the cookie Hover sets after a correct
+// username/password when the account still needs a 2FA code.
+const challengeCookieName = "hover_session"
+
+// ErrNoChallenge is returned by SubmitTwoFactorCode when Login did not
+// leave a pending 2FA challenge to answer.
+var ErrNoChallenge = errors.New("hover: no pending two-factor challenge")
+
+// Client is a Hover API client. The zero value is not usable; construct one
+// with NewClient.
+type Client struct {
+	HTTPClient *http.Client
+
+	username  string
+	password  string
+	cookie    *http.Cookie // hoverauth, set once fully authenticated
+	challenge *http.Cookie // hover_session, set while a 2FA code is pending
+}
+
+// NewClient returns a Client that will authenticate as username/password.
+// Call Login (and SubmitTwoFactorCode, if required) before making any other
+// request, or restore a previously persisted session with SetSessionToken.
+func NewClient(username, password string) *Client {
+	return &Client{
+		HTTPClient: http.DefaultClient,
+		username:   username,
+		password:   password,
+	}
+}
+
+// SessionToken returns the value of the client's current hoverauth cookie,
+// suitable for persisting in a secret store and later restoring via
+// SetSessionToken. The second return value is false if the client is not
+// currently authenticated.
+func (c *Client) SessionToken() (string, bool) {
+	if c.cookie == nil {
+		return "", false
+	}
+
+	return c.cookie.Value, true
+}
+
+// SetSessionToken restores a hoverauth cookie previously obtained from
+// SessionToken, skipping the username/password/2FA flow. Callers should
+// still handle ErrUnauthorized from subsequent requests, since the session
+// may have expired since it was persisted.
+func (c *Client) SetSessionToken(token string) {
+	c.cookie = &http.Cookie{Name: sessionCookieName, Value: token}
+}
+
+// Login authenticates against the Hover API with username/password. If the
+// account has 2FA enabled, Login returns requires2FA=true and the Client
+// holds a pending challenge; call SubmitTwoFactorCode with the TOTP/SMS
+// code to finish authenticating. Otherwise the Client is immediately ready
+// to use.
+func (c *Client) Login() (requires2FA bool, err error) {
+	// Clear any session left by a prior SetSessionToken or Login call, so
+	// success below can only mean this call's response set a fresh cookie,
+	// not that a stale one was already sitting on the Client.
+	c.cookie = nil
+	c.challenge = nil
+
+	v := url.Values{}
+	v.Set("username", c.username)
+	v.Set("password", c.password)
+
+	login, err := c.postAuth(BaseURL+"/login", v)
+	if err != nil {
+		return false, err
+	}
+
+	if c.cookie != nil {
+		return false, nil
+	}
+
+	if c.challenge != nil {
+		return true, nil
+	}
+
+	return false, fmt.Errorf("hover: could not login: %s", login.Error)
+}
+
+// SubmitTwoFactorCode answers the 2FA challenge left by Login with code,
+// completing authentication.
+func (c *Client) SubmitTwoFactorCode(code string) error {
+	if c.challenge == nil {
+		return ErrNoChallenge
+	}
+
+	v := url.Values{}
+	v.Set("code", code)
+
+	req, err := http.NewRequest(http.MethodPost, BaseURL+"/2fa", strings.NewReader(v.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(c.challenge)
+
+	login, err := c.doAuth(req)
+	if err != nil {
+		return err
+	}
+
+	c.challenge = nil
+
+	if c.cookie == nil {
+		return fmt.Errorf("hover: could not verify two-factor code: %s", login.Error)
+	}
+
+	return nil
+}
+
+type authResponse struct {
+	Succeeded bool   `json:"succeeded"`
+	Status    string `json:"status"`
+	Error     string `json:"error"`
+}
+
+// postAuth POSTs form to url and records any hoverauth/hover_session
+// cookies the response sets.
+func (c *Client) postAuth(url string, form url.Values) (*authResponse, error) {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return c.doAuth(req)
+}
+
+func (c *Client) doAuth(req *http.Request) (*authResponse, error) {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	for _, cookie := range resp.Cookies() {
+		switch cookie.Name {
+		case sessionCookieName:
+			c.cookie = cookie
+		case challengeCookieName:
+			c.challenge = cookie
+		}
+	}
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	login := &authResponse{}
+	if err = json.Unmarshal(content, login); err != nil {
+		return nil, err
+	}
+
+	return login, nil
+}
+
+// domainEntries is the shape of a single domain as returned by GET /dns,
+// including its DNS entries.
+type domainEntries struct {
+	Domain
+	Entries []*DNSRecord `json:"entries"`
+}
+
+func (c *Client) dnsDomains() ([]*domainEntries, error) {
+	req, err := http.NewRequest(http.MethodGet, BaseURL+"/dns", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.AddCookie(c.cookie)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, ErrUnauthorized
+	}
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	dns := &struct {
+		Succeeded bool             `json:"succeeded"`
+		Domains   []*domainEntries `json:"domains"`
+	}{}
+	if err = json.Unmarshal(content, dns); err != nil {
+		return nil, err
+	}
+
+	if !dns.Succeeded {
+		return nil, fmt.Errorf("hover: failed to list domains")
+	}
+
+	return dns.Domains, nil
+}
+
+// ListDomains returns every domain owned by the account.
+func (c *Client) ListDomains() ([]*Domain, error) {
+	domains, err := c.dnsDomains()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*Domain, 0, len(domains))
+	for _, d := range domains {
+		domain := d.Domain
+		out = append(out, &domain)
+	}
+
+	return out, nil
+}
+
+// ListRecords returns the DNS records for the given domain name.
+func (c *Client) ListRecords(domain string) ([]*DNSRecord, error) {
+	domains, err := c.dnsDomains()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range domains {
+		if d.Name == domain {
+			return d.Entries, nil
+		}
+	}
+
+	return nil, fmt.Errorf("hover: domain %q not found", domain)
+}
+
+func (c *Client) findDomainID(domain string) (string, error) {
+	domains, err := c.dnsDomains()
+	if err != nil {
+		return "", err
+	}
+
+	for _, d := range domains {
+		if d.Name == domain {
+			return d.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("hover: domain %q not found", domain)
+}
+
+// CreateRecord adds a new DNS record of the given type to domain and
+// returns it.
+func (c *Client) CreateRecord(domain, name, rtype, content string, ttl int) (*DNSRecord, error) {
+	domainID, err := c.findDomainID(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	v := url.Values{}
+	v.Set("name", name)
+	v.Set("type", rtype)
+	v.Set("content", content)
+	if ttl > 0 {
+		v.Set("ttl", strconv.Itoa(ttl))
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/domains/%s/dns", BaseURL, domainID), strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(c.cookie)
+
+	created := &struct {
+		Succeeded bool   `json:"succeeded"`
+		Error     string `json:"error"`
+		ID        string `json:"id"`
+	}{}
+	if err := c.do(req, created); err != nil {
+		return nil, err
+	}
+	if !created.Succeeded {
+		return nil, fmt.Errorf("hover: %s", created.Error)
+	}
+
+	return &DNSRecord{ID: created.ID, Name: name, Type: rtype, Content: content, TTL: ttl}, nil
+}
+
+// UpdateRecord changes the content of an existing DNS record by id.
+func (c *Client) UpdateRecord(id, content string) error {
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/dns/%s", BaseURL, id), nil)
+	if err != nil {
+		return err
+	}
+	q := req.URL.Query()
+	q.Add("content", content)
+	req.URL.RawQuery = q.Encode()
+	req.AddCookie(c.cookie)
+
+	success := &struct {
+		Succeeded bool   `json:"succeeded"`
+		Error     string `json:"error"`
+	}{}
+	if err := c.do(req, success); err != nil {
+		return err
+	}
+	if !success.Succeeded {
+		return fmt.Errorf("hover: %s", success.Error)
+	}
+
+	return nil
+}
+
+// DeleteRecord removes a DNS record by id.
+func (c *Client) DeleteRecord(id string) error {
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/dns/%s", BaseURL, id), nil)
+	if err != nil {
+		return err
+	}
+	req.AddCookie(c.cookie)
+
+	success := &struct {
+		Succeeded bool   `json:"succeeded"`
+		Error     string `json:"error"`
+	}{}
+	if err := c.do(req, success); err != nil {
+		return err
+	}
+	if !success.Succeeded {
+		return fmt.Errorf("hover: %s", success.Error)
+	}
+
+	return nil
+}
+
+// do executes req and decodes its JSON body into out.
+func (c *Client) do(req *http.Request, out interface{}) error {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return ErrUnauthorized
+	}
+
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, out)
+}
@@ -0,0 +1,193 @@
+package hover
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSplitHost(t *testing.T) {
+	domains := []*Domain{
+		{Name: "example.com"},
+		{Name: "example.net"},
+	}
+
+	tests := []struct {
+		name     string
+		host     string
+		wantBase string
+		wantName string
+		wantErr  bool
+	}{
+		{name: "root", host: "example.com", wantBase: "example.com", wantName: "@"},
+		{name: "subdomain", host: "home.example.com", wantBase: "example.com", wantName: "home"},
+		{name: "nested subdomain", host: "a.b.example.com", wantBase: "example.com", wantName: "a.b"},
+		{name: "other owned domain", host: "example.net", wantBase: "example.net", wantName: "@"},
+		{name: "not a suffix match", host: "notexample.com", wantErr: true},
+		{name: "unowned domain", host: "example.org", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, name, err := SplitHost(domains, tt.host)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("SplitHost(%q) = %q, %q, <nil>, want error", tt.host, base, name)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("SplitHost(%q) returned unexpected error: %v", tt.host, err)
+			}
+			if base != tt.wantBase || name != tt.wantName {
+				t.Errorf("SplitHost(%q) = %q, %q, want %q, %q", tt.host, base, name, tt.wantBase, tt.wantName)
+			}
+		})
+	}
+}
+
+// withTestServer points BaseURL at a test server for the duration of the
+// test and returns an already-authenticated Client, so CRUD methods can be
+// exercised without going through Login.
+func withTestServer(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	prevBaseURL := BaseURL
+	BaseURL = srv.URL
+	t.Cleanup(func() { BaseURL = prevBaseURL })
+
+	client := NewClient("user", "pass")
+	client.cookie = &http.Cookie{Name: sessionCookieName, Value: "test-session"}
+
+	return client
+}
+
+func TestClientListDomains(t *testing.T) {
+	client := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/dns" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if _, err := r.Cookie(sessionCookieName); err != nil {
+			t.Errorf("request missing session cookie: %v", err)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"succeeded": true,
+			"domains": []map[string]interface{}{
+				{"id": "dom1", "domain_name": "example.com", "active": true},
+			},
+		})
+	})
+
+	domains, err := client.ListDomains()
+	if err != nil {
+		t.Fatalf("ListDomains: %v", err)
+	}
+	if len(domains) != 1 || domains[0].Name != "example.com" || domains[0].ID != "dom1" {
+		t.Errorf("ListDomains = %+v, want one domain example.com/dom1", domains)
+	}
+}
+
+func TestClientListDomainsUnauthorized(t *testing.T) {
+	client := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	if _, err := client.ListDomains(); err != ErrUnauthorized {
+		t.Fatalf("ListDomains error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestClientListRecordsUnknownDomain(t *testing.T) {
+	client := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"succeeded": true,
+			"domains": []map[string]interface{}{
+				{"id": "dom1", "domain_name": "example.com", "active": true},
+			},
+		})
+	})
+
+	if _, err := client.ListRecords("example.org"); err == nil {
+		t.Fatal("ListRecords succeeded for a domain not on the account, want error")
+	}
+}
+
+func TestClientCreateUpdateDeleteRecord(t *testing.T) {
+	var created, updated, deleted bool
+
+	client := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/dns":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"succeeded": true,
+				"domains": []map[string]interface{}{
+					{"id": "dom1", "domain_name": "example.com", "active": true},
+				},
+			})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/domains/dom1/dns":
+			created = true
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true, "id": "rec1"})
+
+		case r.Method == http.MethodPut && r.URL.Path == "/dns/rec1":
+			updated = true
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true})
+
+		case r.Method == http.MethodDelete && r.URL.Path == "/dns/rec1":
+			deleted = true
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true})
+
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	record, err := client.CreateRecord("example.com", "home", "A", "1.2.3.4", 300)
+	if err != nil {
+		t.Fatalf("CreateRecord: %v", err)
+	}
+	if record.ID != "rec1" || !created {
+		t.Errorf("CreateRecord = %+v, created=%v", record, created)
+	}
+
+	if err := client.UpdateRecord("rec1", "5.6.7.8"); err != nil {
+		t.Fatalf("UpdateRecord: %v", err)
+	}
+	if !updated {
+		t.Error("UpdateRecord did not reach the server")
+	}
+
+	if err := client.DeleteRecord("rec1"); err != nil {
+		t.Fatalf("DeleteRecord: %v", err)
+	}
+	if !deleted {
+		t.Error("DeleteRecord did not reach the server")
+	}
+}
+
+func TestClientCreateRecordFailure(t *testing.T) {
+	client := withTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/dns":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"succeeded": true,
+				"domains": []map[string]interface{}{
+					{"id": "dom1", "domain_name": "example.com", "active": true},
+				},
+			})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": false, "error": "quota exceeded"})
+		}
+	})
+
+	if _, err := client.CreateRecord("example.com", "home", "A", "1.2.3.4", 300); err == nil {
+		t.Fatal("CreateRecord succeeded, want error from a failed response")
+	}
+}
@@ -0,0 +1,222 @@
+// Package ipdetect discovers the public IPv4/IPv6 address of the host by
+// concurrently querying a configurable set of third-party "what's my IP"
+// services.
+package ipdetect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Family selects which IP address family to resolve.
+type Family string
+
+// Supported families.
+const (
+	IPv4 Family = "tcp4"
+	IPv6 Family = "tcp6"
+)
+
+// Aggregation modes for Detector.Mode.
+const (
+	// ModeFirstSuccess returns the first address any provider reports.
+	ModeFirstSuccess = "first"
+	// ModeMajority returns the address reported by the most providers.
+	ModeMajority = "majority"
+)
+
+type parseFunc func([]byte) (string, error)
+
+type provider struct {
+	name  string
+	url   string
+	parse parseFunc
+}
+
+// Names of the built-in providers, for use in Detector.Providers.
+const (
+	MyExternalIP = "myexternalip"
+	Ipify        = "ipify"
+	Icanhazip    = "icanhazip"
+	Cloudflare   = "cloudflare"
+)
+
+var defaultProviders = []provider{
+	{MyExternalIP, "https://myexternalip.com/json", parseJSONIP},
+	{Ipify, "https://api.ipify.org", parsePlainIP},
+	{Icanhazip, "https://icanhazip.com", parsePlainIP},
+	{Cloudflare, "https://1.1.1.1/cdn-cgi/trace", parseCloudflareTrace},
+}
+
+// Detector resolves the public IP address of the host.
+type Detector struct {
+	// Providers restricts which built-in providers are queried, by name.
+	// A nil or empty slice queries all of them.
+	Providers []string
+	// Mode is one of ModeFirstSuccess or ModeMajority. Defaults to
+	// ModeFirstSuccess.
+	Mode string
+	// Timeout bounds each individual provider request. Defaults to 5s.
+	Timeout time.Duration
+}
+
+// Detect returns the host's public address for family, querying every
+// configured provider concurrently.
+func (d *Detector) Detect(family Family) (string, error) {
+	providers := d.providers()
+	if len(providers) == 0 {
+		return "", fmt.Errorf("ipdetect: no providers configured")
+	}
+
+	results := make(chan string, len(providers))
+
+	var wg sync.WaitGroup
+	for _, p := range providers {
+		wg.Add(1)
+		go func(p provider) {
+			defer wg.Done()
+			if ip, err := d.query(p, family); err == nil {
+				results <- ip
+			}
+		}(p)
+	}
+
+	if d.Mode == ModeMajority {
+		wg.Wait()
+		close(results)
+		return majority(results)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for ip := range results {
+		return ip, nil
+	}
+
+	return "", fmt.Errorf("ipdetect: no provider returned an address")
+}
+
+func (d *Detector) providers() []provider {
+	if len(d.Providers) == 0 {
+		return defaultProviders
+	}
+
+	var out []provider
+	for _, name := range d.Providers {
+		for _, p := range defaultProviders {
+			if p.name == name {
+				out = append(out, p)
+			}
+		}
+	}
+
+	return out
+}
+
+func (d *Detector) timeout() time.Duration {
+	if d.Timeout > 0 {
+		return d.Timeout
+	}
+
+	return 5 * time.Second
+}
+
+func (d *Detector) query(p provider, family Family) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout())
+	defer cancel()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, string(family), addr)
+			},
+		},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var content []byte
+	if content, err = io.ReadAll(resp.Body); err != nil {
+		return "", err
+	}
+
+	return p.parse(content)
+}
+
+func majority(results <-chan string) (string, error) {
+	counts := make(map[string]int)
+	var order []string
+
+	for ip := range results {
+		if counts[ip] == 0 {
+			order = append(order, ip)
+		}
+		counts[ip]++
+	}
+
+	if len(order) == 0 {
+		return "", fmt.Errorf("ipdetect: no provider returned an address")
+	}
+
+	best := order[0]
+	for _, ip := range order[1:] {
+		if counts[ip] > counts[best] {
+			best = ip
+		}
+	}
+
+	return best, nil
+}
+
+func parseJSONIP(content []byte) (string, error) {
+	out := &struct {
+		IP string `json:"ip"`
+	}{}
+	if err := json.Unmarshal(content, out); err != nil {
+		return "", err
+	}
+
+	return parsePlainIP([]byte(out.IP))
+}
+
+func parsePlainIP(content []byte) (string, error) {
+	addr := strings.TrimSpace(string(content))
+	if net.ParseIP(addr) == nil {
+		return "", fmt.Errorf("ipdetect: %q is not an IP address", addr)
+	}
+
+	return addr, nil
+}
+
+// parseCloudflareTrace parses Cloudflare's "key=value" trace format and
+// extracts the "ip" field.
+func parseCloudflareTrace(content []byte) (string, error) {
+	for _, line := range strings.Split(string(content), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if ok && key == "ip" {
+			return parsePlainIP([]byte(value))
+		}
+	}
+
+	return "", fmt.Errorf("ipdetect: no ip field in cloudflare trace response")
+}
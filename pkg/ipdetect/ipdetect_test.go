@@ -0,0 +1,154 @@
+package ipdetect
+
+import "testing"
+
+func TestParseJSONIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "valid", content: `{"ip":"203.0.113.7"}`, want: "203.0.113.7"},
+		{name: "ipv6", content: `{"ip":"2001:db8::1"}`, want: "2001:db8::1"},
+		{name: "malformed json", content: `not json`, wantErr: true},
+		{name: "empty body", content: ``, wantErr: true},
+		{name: "not an ip", content: `{"ip":"hello"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseJSONIP([]byte(tt.content))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseJSONIP(%q) = %q, <nil>, want error", tt.content, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseJSONIP(%q) returned unexpected error: %v", tt.content, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseJSONIP(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePlainIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "valid with trailing newline", content: "203.0.113.7\n", want: "203.0.113.7"},
+		{name: "valid ipv6", content: "2001:db8::1", want: "2001:db8::1"},
+		{name: "garbage", content: "not an ip", wantErr: true},
+		{name: "empty", content: "", wantErr: true},
+		{name: "html error page", content: "<html>503</html>", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePlainIP([]byte(tt.content))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parsePlainIP(%q) = %q, <nil>, want error", tt.content, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parsePlainIP(%q) returned unexpected error: %v", tt.content, err)
+			}
+			if got != tt.want {
+				t.Errorf("parsePlainIP(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCloudflareTrace(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "valid trace",
+			content: "fl=1f1\nh=1.1.1.1\nip=203.0.113.7\nts=1234.5\nvisit_scheme=https\n",
+			want:    "203.0.113.7",
+		},
+		{name: "no ip field", content: "fl=1f1\nh=1.1.1.1\n", wantErr: true},
+		{name: "ip field not an ip", content: "ip=not-an-ip\n", wantErr: true},
+		{name: "empty body", content: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCloudflareTrace([]byte(tt.content))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseCloudflareTrace(%q) = %q, <nil>, want error", tt.content, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseCloudflareTrace(%q) returned unexpected error: %v", tt.content, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseCloudflareTrace(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMajority(t *testing.T) {
+	send := func(ips ...string) <-chan string {
+		ch := make(chan string, len(ips))
+		for _, ip := range ips {
+			ch <- ip
+		}
+		close(ch)
+		return ch
+	}
+
+	tests := []struct {
+		name    string
+		ips     []string
+		want    string
+		wantErr bool
+	}{
+		{name: "clear winner", ips: []string{"1.1.1.1", "2.2.2.2", "1.1.1.1"}, want: "1.1.1.1"},
+		{
+			name: "tie breaks to first seen",
+			ips:  []string{"2.2.2.2", "1.1.1.1", "2.2.2.2", "1.1.1.1"},
+			want: "2.2.2.2",
+		},
+		{name: "single vote", ips: []string{"1.1.1.1"}, want: "1.1.1.1"},
+		{name: "no votes", ips: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := majority(send(tt.ips...))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("majority(%v) = %q, <nil>, want error", tt.ips, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("majority(%v) returned unexpected error: %v", tt.ips, err)
+			}
+			if got != tt.want {
+				t.Errorf("majority(%v) = %q, want %q", tt.ips, got, tt.want)
+			}
+		})
+	}
+}
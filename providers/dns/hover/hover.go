@@ -0,0 +1,91 @@
+// Package hover implements a DNS-01 challenge provider for domains hosted
+// on Hover, using the pkg/hover client.
+package hover
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+
+	"github.com/FryDay/hoverDDNS/internal/auth"
+	"github.com/FryDay/hoverDDNS/pkg/hover"
+)
+
+const recordTTL = 300
+
+// DNSProvider implements the challenge.Provider interface.
+type DNSProvider struct {
+	client *hover.Client
+
+	recordIDs map[string]string
+}
+
+// NewDNSProvider returns a DNSProvider authenticated against Hover with
+// username and password. For accounts with 2FA enabled, run
+// "hoverddns login" once first so a session is ready in the OS keyring;
+// password may then be left empty.
+func NewDNSProvider(username, password string) (*DNSProvider, error) {
+	if username == "" {
+		return nil, fmt.Errorf("hover: credentials missing")
+	}
+
+	client, err := auth.Open(username, password)
+	if err != nil {
+		return nil, fmt.Errorf("hover: %w", err)
+	}
+
+	return &DNSProvider{
+		client:    client,
+		recordIDs: make(map[string]string),
+	}, nil
+}
+
+// Timeout returns the timeout and interval to use when checking for DNS
+// propagation, tuned to Hover's slower-than-average propagation.
+func (d *DNSProvider) Timeout() (timeout, interval time.Duration) {
+	return 10 * time.Minute, 10 * time.Second
+}
+
+// Present creates a TXT record to fulfill the dns-01 challenge.
+func (d *DNSProvider) Present(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	authZone, err := dns01.FindZoneByFqdn(info.EffectiveFQDN)
+	if err != nil {
+		return fmt.Errorf("hover: could not find zone for domain %q: %w", domain, err)
+	}
+	authZone = dns01.UnFqdn(authZone)
+
+	subDomain, err := dns01.ExtractSubDomain(info.EffectiveFQDN, authZone)
+	if err != nil {
+		return fmt.Errorf("hover: %w", err)
+	}
+
+	record, err := d.client.CreateRecord(authZone, subDomain, "TXT", info.Value, recordTTL)
+	if err != nil {
+		return fmt.Errorf("hover: failed to create TXT record: %w", err)
+	}
+
+	d.recordIDs[info.EffectiveFQDN] = record.ID
+
+	return nil
+}
+
+// CleanUp removes the TXT record created by Present.
+func (d *DNSProvider) CleanUp(domain, token, keyAuth string) error {
+	info := dns01.GetChallengeInfo(domain, keyAuth)
+
+	id, ok := d.recordIDs[info.EffectiveFQDN]
+	if !ok {
+		return fmt.Errorf("hover: no record found for %q", info.EffectiveFQDN)
+	}
+
+	if err := d.client.DeleteRecord(id); err != nil {
+		return fmt.Errorf("hover: failed to remove TXT record: %w", err)
+	}
+
+	delete(d.recordIDs, info.EffectiveFQDN)
+
+	return nil
+}
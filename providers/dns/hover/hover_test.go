@@ -0,0 +1,164 @@
+package hover
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-acme/lego/v4/challenge/dns01"
+	"github.com/miekg/dns"
+
+	"github.com/FryDay/hoverDDNS/pkg/hover"
+)
+
+// withProvider points hover.BaseURL at a test server driven by handler and
+// returns a DNSProvider with an already-authenticated client, bypassing
+// NewDNSProvider's auth.Open call (and the OS keyring it touches).
+func withProvider(t *testing.T, handler http.HandlerFunc) *DNSProvider {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	prevBaseURL := hover.BaseURL
+	hover.BaseURL = srv.URL
+	t.Cleanup(func() { hover.BaseURL = prevBaseURL })
+
+	client := hover.NewClient("user", "pass")
+	client.SetSessionToken("test-session")
+
+	return &DNSProvider{client: client, recordIDs: make(map[string]string)}
+}
+
+// withFakeDNS answers SOA queries for zone (a trailing-dot fqdn, e.g.
+// "example.com.") with a canned SOA record and NXDOMAINs everything else,
+// then redirects lego's dns01 zone lookups to it. The redirect is not undone
+// when the test ends; the server itself is shut down. This keeps Present's dns01.FindZoneByFqdn call (which otherwise
+// performs a real recursive DNS lookup) deterministic and offline.
+func withFakeDNS(t *testing.T, zone string) {
+	t.Helper()
+
+	soa := &dns.SOA{
+		Hdr:     dns.RR_Header{Name: zone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 3600},
+		Ns:      "ns1." + zone,
+		Mbox:    "hostmaster." + zone,
+		Serial:  1,
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		Minttl:  300,
+	}
+
+	mux := dns.NewServeMux()
+	mux.HandleFunc(".", func(w dns.ResponseWriter, r *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(r)
+
+		if len(r.Question) == 1 && r.Question[0].Qtype == dns.TypeSOA && r.Question[0].Name == zone {
+			m.Answer = append(m.Answer, soa)
+		} else {
+			m.Rcode = dns.RcodeNameError
+		}
+
+		_ = w.WriteMsg(m)
+	})
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &dns.Server{PacketConn: pc, Handler: mux}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+
+	dns01.ClearFqdnCache()
+	t.Cleanup(dns01.ClearFqdnCache)
+	dns01.AddRecursiveNameservers([]string{pc.LocalAddr().String()})(nil)
+}
+
+func TestPresentAndCleanUp(t *testing.T) {
+	withFakeDNS(t, "example.com.")
+
+	var created, deleted bool
+
+	provider := withProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/dns":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"succeeded": true,
+				"domains": []map[string]interface{}{
+					{"id": "dom1", "domain_name": "example.com", "active": true},
+				},
+			})
+
+		case r.Method == http.MethodPost && r.URL.Path == "/domains/dom1/dns":
+			created = true
+			if err := r.ParseForm(); err != nil {
+				t.Fatal(err)
+			}
+			if r.FormValue("name") != "_acme-challenge" {
+				t.Errorf("CreateRecord name = %q, want %q", r.FormValue("name"), "_acme-challenge")
+			}
+			if r.FormValue("type") != "TXT" {
+				t.Errorf("CreateRecord type = %q, want TXT", r.FormValue("type"))
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true, "id": "rec1"})
+
+		case r.Method == http.MethodDelete && r.URL.Path == "/dns/rec1":
+			deleted = true
+			json.NewEncoder(w).Encode(map[string]interface{}{"succeeded": true})
+
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	domain := "example.com"
+	token := "token"
+	keyAuth := "key-auth"
+
+	if err := provider.Present(domain, token, keyAuth); err != nil {
+		t.Fatalf("Present: %v", err)
+	}
+	if !created {
+		t.Error("Present did not create a TXT record")
+	}
+	if len(provider.recordIDs) != 1 {
+		t.Errorf("recordIDs after Present = %v, want exactly one entry", provider.recordIDs)
+	}
+
+	if err := provider.CleanUp(domain, token, keyAuth); err != nil {
+		t.Fatalf("CleanUp: %v", err)
+	}
+	if !deleted {
+		t.Error("CleanUp did not delete the TXT record")
+	}
+	if len(provider.recordIDs) != 0 {
+		t.Errorf("recordIDs after CleanUp = %v, want empty", provider.recordIDs)
+	}
+}
+
+func TestCleanUpWithoutPresent(t *testing.T) {
+	provider := withProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	})
+
+	if err := provider.CleanUp("example.com", "token", "key-auth"); err == nil {
+		t.Fatal("CleanUp succeeded with no record created by Present, want error")
+	}
+}
+
+func TestPresentUnsupportedZone(t *testing.T) {
+	withFakeDNS(t, "never-matches.invalid.")
+
+	provider := withProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	})
+
+	if err := provider.Present("example.org", "token", "key-auth"); err == nil {
+		t.Fatal("Present succeeded for an unresolvable zone, want error")
+	}
+}